@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// Severity classifies how serious a RepoError is, for filtering via -fail-on.
+type Severity int
+
+const (
+	// Warn indicates a problem that didn't prevent the repo from being
+	// reported on, but means some of its fields may be incomplete (e.g. a
+	// remote lookup failed and fell back to a cached or default value).
+	Warn Severity = iota
+	// Error indicates a problem that prevented the repo from being
+	// reported on at all (e.g. its import path couldn't be resolved).
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// RepoError records an error encountered while processing a single repo,
+// along with which stage of the pipeline produced it.
+type RepoError struct {
+	// Repo is the import path or root being processed when Err occurred.
+	// It may be empty if the error occurred before a repo could be identified.
+	Repo string
+	// Stage names the pipeline stage that produced Err, e.g. "resolve", "vcs".
+	Stage    string
+	Severity Severity
+	Err      error
+}
+
+func (e *RepoError) Error() string {
+	if e.Repo == "" {
+		return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Repo, e.Stage, e.Err)
+}
+
+func (e *RepoError) Unwrap() error { return e.Err }
+
+// parseSeverity parses a -fail-on flag value ("warn" or "error") into a Severity.
+func parseSeverity(s string) (Severity, error) {
+	switch s {
+	case "warn":
+		return Warn, nil
+	case "error":
+		return Error, nil
+	default:
+		return 0, fmt.Errorf("invalid -fail-on value %q: must be %q or %q", s, "warn", "error")
+	}
+}
+
+// reportError sends a RepoError built from repo, stage, severity, and err to
+// w.Errors. It's a no-op if err is nil, so call sites can use it unconditionally.
+func (w *workspace) reportError(repo, stage string, severity Severity, err error) {
+	if err == nil {
+		return
+	}
+	w.Errors <- &RepoError{Repo: repo, Stage: stage, Severity: severity, Err: err}
+}
+
+// DrainErrors consumes w.Errors, logging each one, and reports whether any
+// error at or above minSeverity was seen. It blocks until w.Errors is
+// closed, so callers must run it concurrently with (not after) ranging
+// over w.Statuses: both channels are fed by the same pipeline workers, and
+// w.Errors is buffered, so leaving it undrained risks a worker blocking
+// forever on a send, which would in turn stall w.Statuses from ever
+// closing.
+func (w *workspace) DrainErrors(minSeverity Severity) (failed bool) {
+	for err := range w.Errors {
+		log.Printf("%v\n", err)
+		if err.Severity >= minSeverity {
+			failed = true
+		}
+	}
+	return failed
+}
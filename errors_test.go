@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Severity
+		wantErr bool
+	}{
+		{name: "warn", in: "warn", want: Warn},
+		{name: "error", in: "error", want: Error},
+		{name: "empty", in: "", wantErr: true},
+		{name: "unknown", in: "fatal", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSeverity(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSeverity(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseSeverity(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mewbak/gostatus/status"
+)
+
+// githubToken authenticates requests to the GitHub API, raising the rate
+// limit from 60 req/hr (unauthenticated) to 5000 req/hr. Defaults to
+// $GITHUB_TOKEN so CI and shell environments don't need to pass the flag.
+var githubToken = flag.String("github-token", os.Getenv("GITHUB_TOKEN"), "GitHub API token, used to raise the rate limit when accelerating remote lookups. Defaults to $GITHUB_TOKEN.")
+
+// RemoteResolver resolves a repo's default remote branch and its current
+// revision directly via a forge's REST API, as a faster alternative to
+// vcsstate.VCS.RemoteBranchAndRevision, which shells out to `git ls-remote`
+// (or the hg/bzr/svn equivalent) and pays a full VCS protocol round-trip.
+//
+// Resolve returns errUnsupportedForge if remoteURL's host isn't a forge it
+// accelerates, and another non-nil error if remoteURL can't be parsed into
+// an owner/repo pair or the API request fails; on any error the caller
+// should fall back to the VCS command.
+type RemoteResolver interface {
+	Resolve(remoteURL string) (branch, revision string, err error)
+}
+
+// errUnsupportedForge indicates remoteURL doesn't belong to a forge this
+// resolver knows how to accelerate.
+var errUnsupportedForge = fmt.Errorf("remote is not a recognized forge")
+
+// forgeResolver accelerates remote lookups for GitHub and GitLab repos by
+// calling their REST APIs instead of shelling out to the VCS. Gitea isn't
+// accelerated: its API shape is GitHub-compatible, but there's no reliable
+// way to tell a self-hosted Gitea instance from an arbitrary git-over-HTTP(S)
+// server from the remote URL alone, so it falls back to the VCS command.
+//
+// Requests are throttled per host (see hostLimiters), independent of the
+// workspace's own worker pool, so a GOPATH with dozens of repos on the same
+// host doesn't fire them all at once and blow through the host's rate limit.
+type forgeResolver struct {
+	httpClient *http.Client
+
+	hostLimitersMu sync.Mutex
+	hostLimiters   map[string]chan struct{}
+}
+
+// maxConcurrentPerHost bounds how many requests forgeResolver has in flight
+// to a single forge host at once.
+const maxConcurrentPerHost = 2
+
+func newForgeResolver() *forgeResolver {
+	return &forgeResolver{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		hostLimiters: make(map[string]chan struct{}),
+	}
+}
+
+// acquire blocks until a slot in host's limiter is free, and returns a func
+// to release it.
+func (f *forgeResolver) acquire(host string) (release func()) {
+	f.hostLimitersMu.Lock()
+	limiter, ok := f.hostLimiters[host]
+	if !ok {
+		limiter = make(chan struct{}, maxConcurrentPerHost)
+		f.hostLimiters[host] = limiter
+	}
+	f.hostLimitersMu.Unlock()
+
+	limiter <- struct{}{}
+	return func() { <-limiter }
+}
+
+func (f *forgeResolver) Resolve(remoteURL string) (branch, revision string, err error) {
+	host, owner, repo, err := parseForgeURL(remoteURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	release := f.acquire(host)
+	defer release()
+
+	switch host {
+	case "github.com":
+		return f.resolveGitHub(owner, repo)
+	case "gitlab.com":
+		return f.resolveGitLab(owner, repo)
+	default:
+		// Most self-hosted Gitea/Gogs instances mirror the GitHub API shape
+		// under /api/v1, but without a way to tell a Gitea host from an
+		// arbitrary git-over-HTTP(S) server, only the well-known forges
+		// above are accelerated; everything else falls back to the VCS.
+		return "", "", errUnsupportedForge
+	}
+}
+
+func (f *forgeResolver) resolveGitHub(owner, repo string) (branch, revision string, err error) {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := f.getJSONAuth(forgeRepoPath("https://api.github.com/repos", owner, repo), *githubToken, &repoInfo); err != nil {
+		return "", "", err
+	}
+
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := f.getJSONAuth(forgeCommitPath("https://api.github.com/repos", owner, repo, repoInfo.DefaultBranch), *githubToken, &commit); err != nil {
+		return "", "", err
+	}
+
+	return repoInfo.DefaultBranch, commit.SHA, nil
+}
+
+func (f *forgeResolver) resolveGitLab(owner, repo string) (branch, revision string, err error) {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	projectPath := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s", forgeProjectID(owner, repo))
+	if err := f.getJSON(projectPath, &repoInfo); err != nil {
+		return "", "", err
+	}
+
+	var branchInfo struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	branchPath := fmt.Sprintf("%s/repository/branches/%s", projectPath, repoInfo.DefaultBranch)
+	if err := f.getJSON(branchPath, &branchInfo); err != nil {
+		return "", "", err
+	}
+
+	return repoInfo.DefaultBranch, branchInfo.Commit.ID, nil
+}
+
+func (f *forgeResolver) getJSON(url string, v interface{}) error {
+	return f.getJSONAuth(url, "", v)
+}
+
+// getJSONAuth is like getJSON, but sends token as a bearer credential if
+// it's non-empty.
+func (f *forgeResolver) getJSONAuth(url, token string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// parseForgeURL extracts the host, owner, and repo name from a repo remote
+// URL, understanding both regular and SCP-style ("git@host:owner/repo")
+// forms by reusing status.ParseRepoURL rather than net/url.Parse directly,
+// since the latter rejects SCP syntax outright.
+func parseForgeURL(remoteURL string) (host, owner, repo string, err error) {
+	u, err := status.ParseRepoURL(remoteURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("parseForgeURL: %v", err)
+	}
+
+	path := strings.Trim(u.Path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("parseForgeURL: can't extract owner/repo from path %q", u.Path)
+	}
+
+	return u.Host, parts[0], parts[1], nil
+}
+
+// forgeRepoPath builds a GitHub-style "<apiRoot>/<owner>/<repo>" API path.
+func forgeRepoPath(apiRoot, owner, repo string) string {
+	return fmt.Sprintf("%s/%s/%s", apiRoot, owner, repo)
+}
+
+// forgeCommitPath builds a GitHub-style path for fetching a single commit by ref.
+func forgeCommitPath(apiRoot, owner, repo, ref string) string {
+	return fmt.Sprintf("%s/commits/%s", forgeRepoPath(apiRoot, owner, repo), ref)
+}
+
+// forgeProjectID builds the URL-encoded "owner/repo" project identifier GitLab's API expects.
+func forgeProjectID(owner, repo string) string {
+	return strings.ReplaceAll(fmt.Sprintf("%s/%s", owner, repo), "/", "%2F")
+}
@@ -0,0 +1,95 @@
+package main
+
+import "testing"
+
+func TestParseForgeURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		remoteURL string
+		wantHost  string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{
+			name:      "https",
+			remoteURL: "https://github.com/shurcooL/gostatus",
+			wantHost:  "github.com",
+			wantOwner: "shurcooL",
+			wantRepo:  "gostatus",
+		},
+		{
+			name:      "https with .git suffix",
+			remoteURL: "https://github.com/shurcooL/gostatus.git",
+			wantHost:  "github.com",
+			wantOwner: "shurcooL",
+			wantRepo:  "gostatus",
+		},
+		{
+			name:      "scp-style ssh",
+			remoteURL: "git@github.com:shurcooL/gostatus.git",
+			wantHost:  "github.com",
+			wantOwner: "shurcooL",
+			wantRepo:  "gostatus",
+		},
+		{
+			name:      "ssh url",
+			remoteURL: "ssh://git@gitlab.com/owner/repo.git",
+			wantHost:  "gitlab.com",
+			wantOwner: "owner",
+			wantRepo:  "repo",
+		},
+		{
+			name:      "missing repo segment",
+			remoteURL: "https://github.com/shurcooL",
+			wantErr:   true,
+		},
+		{
+			name:      "unparseable",
+			remoteURL: "://not a url",
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, owner, repo, err := parseForgeURL(test.remoteURL)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("parseForgeURL(%q): got nil error, want error", test.remoteURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseForgeURL(%q): unexpected error: %v", test.remoteURL, err)
+			}
+			if host != test.wantHost || owner != test.wantOwner || repo != test.wantRepo {
+				t.Errorf("parseForgeURL(%q) = %q, %q, %q; want %q, %q, %q",
+					test.remoteURL, host, owner, repo, test.wantHost, test.wantOwner, test.wantRepo)
+			}
+		})
+	}
+}
+
+func TestForgeRepoPath(t *testing.T) {
+	got := forgeRepoPath("https://api.github.com/repos", "shurcooL", "gostatus")
+	want := "https://api.github.com/repos/shurcooL/gostatus"
+	if got != want {
+		t.Errorf("forgeRepoPath: got %q, want %q", got, want)
+	}
+}
+
+func TestForgeCommitPath(t *testing.T) {
+	got := forgeCommitPath("https://api.github.com/repos", "shurcooL", "gostatus", "master")
+	want := "https://api.github.com/repos/shurcooL/gostatus/commits/master"
+	if got != want {
+		t.Errorf("forgeCommitPath: got %q, want %q", got, want)
+	}
+}
+
+func TestForgeProjectID(t *testing.T) {
+	got := forgeProjectID("owner", "repo")
+	want := "owner%2Frepo"
+	if got != want {
+		t.Errorf("forgeProjectID: got %q, want %q", got, want)
+	}
+}
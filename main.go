@@ -0,0 +1,143 @@
+// gostatus is a command line tool that shows the status of Go repositories.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/kisielk/gotool"
+)
+
+// parallelism for workers.
+const parallelism = 8
+
+var (
+	debugFlag   = flag.Bool("debug", false, "Cause the repository data to be printed in verbose debug format.")
+	fFlag       = flag.Bool("f", false, "Force not to verify that each package has been checked out from the source control repository implied by its import path. This can be useful if the source is a local fork of the original.")
+	stdinFlag   = flag.Bool("stdin", false, "Read the list of newline separated Go packages from stdin.")
+	vFlag       = flag.Bool("v", false, "Verbose mode. Show all Go packages, not just ones with notable status.")
+	compactFlag = flag.Bool("c", false, "Compact output with inline notation.")
+	failOn      = flag.String("fail-on", "", `Exit with a non-zero status if any repo-processing error is at or above this severity ("warn" or "error"). Errors are logged regardless; leave empty to never fail because of them.`)
+)
+
+func usage() {
+	fmt.Fprint(os.Stderr, "Usage: gostatus [flags] [packages]\n")
+	fmt.Fprint(os.Stderr, "       [newline separated packages] | gostatus -stdin [flags]\n")
+	flag.PrintDefaults()
+	fmt.Fprint(os.Stderr, `
+Examples:
+  # Show status of package in current directory.
+  gostatus
+
+  # Show status of all dependencies (recursive) of package in current dir.
+  go list -deps | gostatus -stdin -v
+
+  # Show status of every repo checked out in GOPATH.
+  gostatus -all
+
+Legend:
+  ? - Not under version control or unreachable remote
+  b - Non-default branch checked out
+  * - Uncommited changes in working dir
+  + - Update available
+  - - Local revision is ahead of remote revision
+  ± - Update available; local revision is ahead of remote revision
+  ! - No remote
+  / - Remote repository not found (was it deleted? made private?)
+  # - Remote path doesn't match import path
+  $ - Stash exists
+`)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	var shouldShow RepoFilter
+	switch {
+	default:
+		shouldShow = func(r *Repo) bool {
+			// Check for notable status.
+			return CompactPresenter(r)[:4] != "    "
+		}
+	case *vFlag:
+		shouldShow = func(*Repo) bool { return true }
+	}
+
+	var presenter RepoPresenter
+	switch {
+	case *format == "json":
+		presenter = JSONPresenter
+	case *debugFlag:
+		presenter = DebugPresenter
+	case *compactFlag:
+		presenter = CompactPresenter
+	default:
+		presenter = PorcelainPresenter
+	}
+
+	var failOnSeverity Severity
+	failOnSet := *failOn != ""
+	if failOnSet {
+		var err error
+		failOnSeverity, err = parseSeverity(*failOn)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	workspace := NewWorkspace(shouldShow, presenter)
+
+	// Feed input into workspace processing pipeline.
+	switch {
+	case *stdinFlag:
+		go func() { // This needs to happen in the background because sending input will be blocked on processing and receiving output.
+			sc := bufio.NewScanner(os.Stdin)
+			for sc.Scan() {
+				workspace.ImportPaths <- sc.Text()
+			}
+			close(workspace.ImportPaths)
+		}()
+	case *all:
+		go func() { // This needs to happen in the background because DiscoverAll will be blocked on processing and receiving output.
+			workspace.DiscoverAll()
+			close(workspace.ImportPaths)
+		}()
+	default:
+		go func() { // This needs to happen in the background because sending input will be blocked on processing and receiving output.
+			importPaths := gotool.ImportPaths(flag.Args())
+			for _, importPath := range importPaths {
+				workspace.ImportPaths <- importPath
+			}
+			close(workspace.ImportPaths)
+		}()
+	}
+
+	// DrainErrors must run concurrently with the Statuses loop below, not
+	// after it: both channels are produced by the same pipeline workers, so
+	// leaving Errors undrained until Statuses closes can deadlock a worker
+	// blocked sending on a full Errors channel.
+	failedCh := make(chan bool, 1)
+	go func() { failedCh <- workspace.DrainErrors(failOnSeverity) }()
+
+	for status := range workspace.Statuses {
+		fmt.Println(status)
+	}
+
+	failed := <-failedCh
+	if failOnSet && failed {
+		os.Exit(1)
+	}
+}
+
+var wd = func() string {
+	// Get current directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalln("failed to get current directory:", err)
+	}
+	return wd
+}()
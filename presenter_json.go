@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+)
+
+var format = flag.String("format", "text", "Output format: text or json.")
+
+// jsonRepo is the JSON representation of a processed *Repo, as emitted by
+// JSONPresenter. Field names are chosen to read well as NDJSON piped into
+// jq, rather than to mirror Repo's Go field names exactly.
+type jsonRepo struct {
+	Path  string    `json:"path"`
+	Root  string    `json:"root"`
+	Local jsonLocal `json:"local"`
+
+	Remote jsonRemote `json:"remote"`
+
+	// State is a derived summary of Local and Remote, for consumers that
+	// want to filter or group without inspecting every field, e.g.
+	// `jq 'select(.state=="dirty")'`.
+	State string `json:"state"`
+}
+
+type jsonLocal struct {
+	Branch                 string `json:"branch"`
+	Revision               string `json:"revision"`
+	Status                 string `json:"status,omitempty"`
+	Stash                  string `json:"stash,omitempty"`
+	RemoteURL              string `json:"remoteURL,omitempty"`
+	ContainsRemoteRevision bool   `json:"containsRemoteRevision"`
+	AheadCount             int    `json:"aheadCount,omitempty"`
+	BehindCount            int    `json:"behindCount,omitempty"`
+	ChangedFiles           int    `json:"changedFiles,omitempty"`
+}
+
+type jsonRemote struct {
+	Branch                string `json:"branch"`
+	Revision              string `json:"revision"`
+	RepoURL               string `json:"repoURL,omitempty"`
+	ContainsLocalRevision bool   `json:"containsLocalRevision"`
+	NotFound              bool   `json:"notFound,omitempty"`
+}
+
+// repoState derives a single-word state enum for r, one of "clean", "dirty",
+// "ahead", "behind", "diverged", "stash", "untracked-vcs", or "not-found".
+func repoState(r *Repo) string {
+	switch {
+	case r.vcs == nil:
+		return "untracked-vcs"
+	case r.Remote.NotFound != nil:
+		return "not-found"
+	case r.Local.Status != "":
+		return "dirty"
+	case r.Local.Stash != "":
+		return "stash"
+	case r.Remote.Revision == "" || r.Local.Revision == r.Remote.Revision:
+		return "clean"
+	case r.Local.ContainsRemoteRevision && r.Remote.ContainsLocalRevision:
+		return "clean"
+	case r.Local.ContainsRemoteRevision:
+		return "ahead"
+	case r.Remote.ContainsLocalRevision:
+		return "behind"
+	default:
+		return "diverged"
+	}
+}
+
+// JSONPresenter renders r as a single-line JSON object, for -format=json.
+// Callers stream one of these per repo, producing NDJSON output that's
+// straightforward to pipe into jq, dashboards, or CI checks.
+func JSONPresenter(r *Repo) string {
+	jr := jsonRepo{
+		Path: r.Path,
+		Root: r.Root,
+		Local: jsonLocal{
+			Branch:                 r.Local.Branch,
+			Revision:               r.Local.Revision,
+			Status:                 r.Local.Status,
+			Stash:                  r.Local.Stash,
+			RemoteURL:              r.Local.RemoteURL,
+			ContainsRemoteRevision: r.Local.ContainsRemoteRevision,
+			AheadCount:             r.Local.AheadCount,
+			BehindCount:            r.Local.BehindCount,
+			ChangedFiles:           r.Local.ChangedFiles,
+		},
+		Remote: jsonRemote{
+			Branch:                r.Remote.Branch,
+			Revision:              r.Remote.Revision,
+			RepoURL:               r.Remote.RepoURL,
+			ContainsLocalRevision: r.Remote.ContainsLocalRevision,
+			NotFound:              r.Remote.NotFound != nil,
+		},
+		State: repoState(r),
+	}
+
+	b, err := json.Marshal(jr)
+	if err != nil {
+		log.Printf("JSONPresenter: %v\n", err)
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shurcooL/vcsstate"
+)
+
+// fakeVCS satisfies vcsstate.VCS by embedding it with a nil value; repoState
+// only ever checks r.vcs for nilness in these tests, never calls a method.
+type fakeVCS struct{ vcsstate.VCS }
+
+func repoWithVCS(mutate func(r *Repo)) *Repo {
+	r := &Repo{vcs: fakeVCS{}}
+	mutate(r)
+	return r
+}
+
+func TestRepoState(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Repo
+		want string
+	}{
+		{
+			name: "untracked",
+			r:    &Repo{},
+			want: "untracked-vcs",
+		},
+		{
+			name: "not found",
+			r:    repoWithVCS(func(r *Repo) { r.Remote.NotFound = errors.New("not found") }),
+			want: "not-found",
+		},
+		{
+			name: "dirty",
+			r:    repoWithVCS(func(r *Repo) { r.Local.Status = "M foo.go" }),
+			want: "dirty",
+		},
+		{
+			name: "stash",
+			r:    repoWithVCS(func(r *Repo) { r.Local.Stash = "WIP" }),
+			want: "stash",
+		},
+		{
+			name: "clean, no remote revision known",
+			r:    repoWithVCS(func(r *Repo) {}),
+			want: "clean",
+		},
+		{
+			name: "clean, revisions match",
+			r: repoWithVCS(func(r *Repo) {
+				r.Local.Revision, r.Remote.Revision = "abc", "abc"
+			}),
+			want: "clean",
+		},
+		{
+			name: "clean, diverged revisions but each contains the other",
+			r: repoWithVCS(func(r *Repo) {
+				r.Local.Revision, r.Remote.Revision = "abc", "def"
+				r.Local.ContainsRemoteRevision, r.Remote.ContainsLocalRevision = true, true
+			}),
+			want: "clean",
+		},
+		{
+			name: "ahead",
+			r: repoWithVCS(func(r *Repo) {
+				r.Local.Revision, r.Remote.Revision = "abc", "def"
+				r.Local.ContainsRemoteRevision = true
+			}),
+			want: "ahead",
+		},
+		{
+			name: "behind",
+			r: repoWithVCS(func(r *Repo) {
+				r.Local.Revision, r.Remote.Revision = "abc", "def"
+				r.Remote.ContainsLocalRevision = true
+			}),
+			want: "behind",
+		},
+		{
+			name: "diverged",
+			r: repoWithVCS(func(r *Repo) {
+				r.Local.Revision, r.Remote.Revision = "abc", "def"
+			}),
+			want: "diverged",
+		},
+	}
+	for _, test := range tests {
+		if got, want := repoState(test.r), test.want; got != want {
+			t.Errorf("%s: repoState: got %q, want %q", test.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	remoteCacheTTL = flag.Duration("remote-cache", 15*time.Minute, "Cache remote branch/revision lookups for this long before refreshing; 0 disables caching.")
+	noRemote       = flag.Bool("no-remote", false, "Don't perform any remote VCS lookups; report local state only.")
+)
+
+// remoteCacheEntry is a memoized remote lookup result for a single repo root.
+type remoteCacheEntry struct {
+	Branch    string    `json:"branch"`
+	Revision  string    `json:"revision"`
+	RepoURL   string    `json:"repoURL"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// remoteCache is an on-disk, mutex-guarded cache of remote VCS state keyed by
+// repo root import path. computeVCSState consults it before shelling out to
+// RemoteBranchAndRevision or RepoRootForImportPath, which are the dominant
+// cost (network round-trips) when scanning a GOPATH with dozens of repos.
+type remoteCache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]remoteCacheEntry
+}
+
+// newRemoteCache loads the on-disk cache, or starts an empty one if it
+// doesn't exist or fails to parse; a cold or corrupt cache just means this
+// run re-fetches everything, not a fatal error.
+func newRemoteCache() *remoteCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	c := &remoteCache{
+		path:    filepath.Join(dir, "gostatus", "remote.json"),
+		entries: make(map[string]remoteCacheEntry),
+	}
+
+	if f, err := os.Open(c.path); err == nil {
+		defer f.Close()
+		json.NewDecoder(f).Decode(&c.entries)
+	}
+
+	return c
+}
+
+// get returns the cached entry for root and whether it's still within TTL.
+func (c *remoteCache) get(root string) (remoteCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[root]
+	if !ok || *remoteCacheTTL <= 0 || time.Since(e.FetchedAt) > *remoteCacheTTL {
+		return remoteCacheEntry{}, false
+	}
+	return e, true
+}
+
+// put records a fresh remote lookup for root.
+func (c *remoteCache) put(root string, e remoteCacheEntry) {
+	e.FetchedAt = time.Now()
+
+	c.mu.Lock()
+	c.entries[root] = e
+	c.mu.Unlock()
+}
+
+// save persists the cache to disk. Errors are logged rather than returned:
+// a failed save just means the next run starts cold.
+func (c *remoteCache) save() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		log.Printf("remoteCache.save: %v\n", err)
+		return
+	}
+	f, err := os.Create(c.path)
+	if err != nil {
+		log.Printf("remoteCache.save: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(c.entries); err != nil {
+		log.Printf("remoteCache.save: %v\n", err)
+	}
+}
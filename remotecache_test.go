@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRemoteCacheGet(t *testing.T) {
+	origTTL := *remoteCacheTTL
+	defer func() { *remoteCacheTTL = origTTL }()
+
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		fetchedAt time.Time
+		present   bool
+		wantOK    bool
+	}{
+		{
+			name:      "fresh entry within TTL",
+			ttl:       15 * time.Minute,
+			fetchedAt: time.Now().Add(-1 * time.Minute),
+			present:   true,
+			wantOK:    true,
+		},
+		{
+			name:      "expired entry past TTL",
+			ttl:       15 * time.Minute,
+			fetchedAt: time.Now().Add(-16 * time.Minute),
+			present:   true,
+			wantOK:    false,
+		},
+		{
+			name:      "caching disabled",
+			ttl:       0,
+			fetchedAt: time.Now(),
+			present:   true,
+			wantOK:    false,
+		},
+		{
+			name:    "no entry",
+			ttl:     15 * time.Minute,
+			present: false,
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*remoteCacheTTL = tt.ttl
+			c := &remoteCache{entries: make(map[string]remoteCacheEntry)}
+			if tt.present {
+				c.entries["root"] = remoteCacheEntry{Branch: "main", FetchedAt: tt.fetchedAt}
+			}
+
+			_, ok := c.get("root")
+			if ok != tt.wantOK {
+				t.Errorf("get() ok = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRemoteCachePutStampsFetchedAt(t *testing.T) {
+	c := &remoteCache{entries: make(map[string]remoteCacheEntry)}
+	before := time.Now()
+	c.put("root", remoteCacheEntry{Branch: "main"})
+	after := time.Now()
+
+	e := c.entries["root"]
+	if e.FetchedAt.Before(before) || e.FetchedAt.After(after) {
+		t.Errorf("put() FetchedAt = %v, want between %v and %v", e.FetchedAt, before, after)
+	}
+}
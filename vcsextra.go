@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// errUnsupportedVCS is returned by aheadBehindCommitCounts and
+// changedFileCount when vcsName names a VCS that feature isn't implemented
+// for (e.g. bzr, svn). It's an expected outcome, not a failure: callers
+// should treat it as a silent zero-value rather than reporting it.
+var errUnsupportedVCS = errors.New("unsupported VCS")
+
+// errNoUpstream is returned by gitAheadBehindCommitCounts when the checked
+// out branch has no tracking upstream configured. It's an expected outcome
+// for freshly created branches, not a failure. A detached HEAD makes git fail
+// with a different message and is returned as a wrapped error instead.
+var errNoUpstream = errors.New("no upstream configured")
+
+// aheadBehindCommitCounts returns the number of commits the local branch at
+// dir is ahead of and behind its upstream, using the VCS named by vcsName.
+// It returns errUnsupportedVCS if vcsName isn't supported, errNoUpstream if
+// the branch has no tracking upstream, and a wrapped error otherwise.
+func aheadBehindCommitCounts(vcsName, dir string) (ahead, behind int, err error) {
+	switch vcsName {
+	case "git":
+		return gitAheadBehindCommitCounts(dir)
+	case "hg":
+		return hgAheadBehindCommitCounts(dir)
+	default:
+		return 0, 0, errUnsupportedVCS
+	}
+}
+
+// changedFileCount returns the number of files with uncommitted changes
+// (staged or unstaged) at dir, using the VCS named by vcsName. It returns
+// errUnsupportedVCS if vcsName isn't supported.
+func changedFileCount(vcsName, dir string) (int, error) {
+	switch vcsName {
+	case "git":
+		return gitChangedFileCount(dir)
+	case "hg":
+		return hgChangedFileCount(dir)
+	default:
+		return 0, errUnsupportedVCS
+	}
+}
+
+// gitAheadBehindCommitCounts shells out to git to count commits the current
+// branch is ahead/behind its upstream (not necessarily "origin"; whatever
+// @{upstream} resolves to). It returns errNoUpstream if the branch has no
+// tracking upstream configured.
+func gitAheadBehindCommitCounts(dir string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "no upstream configured") {
+			return 0, 0, errNoUpstream
+		}
+		return 0, 0, fmt.Errorf("gitAheadBehindCommitCounts: %v", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("gitAheadBehindCommitCounts: unexpected output %q", out)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("gitAheadBehindCommitCounts: %v", err)
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("gitAheadBehindCommitCounts: %v", err)
+	}
+	return ahead, behind, nil
+}
+
+// gitShortstatRE matches the file count out of `git diff --shortstat` output,
+// e.g. " 3 files changed, 10 insertions(+), 2 deletions(-)".
+var gitShortstatRE = regexp.MustCompile(`(\d+) files? changed`)
+
+// gitChangedFileCount counts files with uncommitted changes at dir. It diffs
+// against HEAD rather than the index, so files that are staged (git add'ed)
+// but not yet committed are counted too, not just worktree-vs-index changes.
+func gitChangedFileCount(dir string) (int, error) {
+	cmd := exec.Command("git", "diff", "--shortstat", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("gitChangedFileCount: %v", err)
+	}
+	if len(out) == 0 {
+		return 0, nil
+	}
+	m := gitShortstatRE.FindStringSubmatch(string(out))
+	if m == nil {
+		return 0, fmt.Errorf("gitChangedFileCount: unexpected output %q", out)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// hgAheadBehindCommitCounts shells out to hg to count changesets the local
+// repository is ahead of and behind its default path, across all branches
+// (no branch is passed to hg outgoing/incoming). outgoing and incoming are
+// run concurrently since they're independent, each potentially a slow
+// network round-trip.
+func hgAheadBehindCommitCounts(dir string) (ahead, behind int, err error) {
+	var wg sync.WaitGroup
+	var outgoingErr, incomingErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ahead, outgoingErr = hgLogCount(dir, "outgoing")
+	}()
+	go func() {
+		defer wg.Done()
+		behind, incomingErr = hgLogCount(dir, "incoming")
+	}()
+	wg.Wait()
+
+	if outgoingErr != nil {
+		return 0, 0, fmt.Errorf("hgAheadBehindCommitCounts: %v", outgoingErr)
+	}
+	if incomingErr != nil {
+		return 0, 0, fmt.Errorf("hgAheadBehindCommitCounts: %v", incomingErr)
+	}
+	return ahead, behind, nil
+}
+
+// hgLogCount runs `hg <subcommand> --quiet --template "{node}\n"` and counts
+// the lines of output. hg exits with status 1 and no output when there's
+// nothing to report, which isn't an error condition here.
+func hgLogCount(dir, subcommand string) (int, error) {
+	cmd := exec.Command("hg", subcommand, "--quiet", "--template", "{node}\n")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return countLines(out), nil
+}
+
+// hgChangedFileCount counts files with uncommitted changes at dir.
+func hgChangedFileCount(dir string) (int, error) {
+	cmd := exec.Command("hg", "status", "--quiet")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("hgChangedFileCount: %v", err)
+	}
+	return countLines(out), nil
+}
+
+// countLines counts the non-empty lines in out, the output of an hg command
+// that prints one record per line. An empty (or all-whitespace) out counts
+// as zero lines, not one.
+func countLines(out []byte) int {
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return 0
+	}
+	return len(strings.Split(trimmed, "\n"))
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestGitShortstatRE(t *testing.T) {
+	tests := []struct {
+		name   string
+		out    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "single file",
+			out:    " 1 file changed, 2 insertions(+)",
+			want:   "1",
+			wantOK: true,
+		},
+		{
+			name:   "multiple files",
+			out:    " 3 files changed, 10 insertions(+), 2 deletions(-)",
+			want:   "3",
+			wantOK: true,
+		},
+		{
+			name:   "no match",
+			out:    "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := gitShortstatRE.FindStringSubmatch(tt.out)
+			if (m != nil) != tt.wantOK {
+				t.Fatalf("FindStringSubmatch(%q) match = %v, want %v", tt.out, m != nil, tt.wantOK)
+			}
+			if m != nil && m[1] != tt.want {
+				t.Errorf("FindStringSubmatch(%q) = %q, want %q", tt.out, m[1], tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name string
+		out  []byte
+		want int
+	}{
+		{name: "empty", out: []byte(""), want: 0},
+		{name: "whitespace only", out: []byte("\n  \n"), want: 0},
+		{name: "single line", out: []byte("abc123\n"), want: 1},
+		{name: "multiple lines", out: []byte("abc123\ndef456\nghi789\n"), want: 3},
+		{name: "no trailing newline", out: []byte("abc123\ndef456"), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countLines(tt.out); got != tt.want {
+				t.Errorf("countLines(%q) = %d, want %d", tt.out, got, tt.want)
+			}
+		})
+	}
+}
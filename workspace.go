@@ -1,9 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"go/build"
-	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -12,17 +14,24 @@ import (
 	"golang.org/x/tools/go/vcs"
 )
 
+var all = flag.Bool("all", false, "Discover and show status of every repo checked out in GOPATH, instead of reading import paths from go list.")
+
 // workspace is a Go workspace environment; each repo has local and remote components.
 type workspace struct {
-	ImportPaths       chan string // ImportPaths is the input for Go packages to be processed.
-	unique            chan *Repo  // Unique repos.
-	processedFiltered chan *Repo  // Processed repos, populated with local and remote state, filtered with shouldShow.
-	Statuses          chan string // Statuses has results of running presenter on processed repos.
-	Errors            chan error  // Errors contains errors that were encountered during processing of repos.
+	ImportPaths       chan string     // ImportPaths is the input for Go packages to be processed.
+	unique            chan *Repo      // Unique repos.
+	processedFiltered chan *Repo      // Processed repos, populated with local and remote state, filtered with shouldShow.
+	Statuses          chan string     // Statuses has results of running presenter on processed repos.
+	Errors            chan *RepoError // Errors contains errors that were encountered during processing of repos.
 
 	shouldShow RepoFilter
 	presenter  RepoPresenter
 
+	remoteCache    *remoteCache   // Memoizes remote branch/revision/URL lookups across runs.
+	remoteResolver RemoteResolver // Accelerates remote branch/revision lookups via forge APIs, falling back to the VCS command on error.
+
+	uniqueWG sync.WaitGroup // Tracks producers feeding the unique channel (uniqueWorker and DiscoverAll).
+
 	reposMu sync.Mutex
 	repos   map[string]*Repo // Map key is the import path corresponding to the root of the repository or Go package.
 }
@@ -33,22 +42,24 @@ func NewWorkspace(shouldShow RepoFilter, presenter RepoPresenter) *workspace {
 		unique:            make(chan *Repo, 64),
 		processedFiltered: make(chan *Repo, 64),
 		Statuses:          make(chan string, 64),
-		Errors:            make(chan error, 64),
+		Errors:            make(chan *RepoError, 64),
 
 		shouldShow: shouldShow,
 		presenter:  presenter,
 
+		remoteCache:    newRemoteCache(),
+		remoteResolver: newForgeResolver(),
+
 		repos: make(map[string]*Repo),
 	}
 
 	{
-		var wg sync.WaitGroup
 		for range iter.N(parallelism) {
-			wg.Add(1)
-			go w.uniqueWorker(&wg)
+			w.uniqueWG.Add(1)
+			go w.uniqueWorker(&w.uniqueWG)
 		}
 		go func() {
-			wg.Wait()
+			w.uniqueWG.Wait()
 			close(w.unique)
 		}()
 	}
@@ -71,6 +82,7 @@ func NewWorkspace(shouldShow RepoFilter, presenter RepoPresenter) *workspace {
 		}
 		go func() {
 			wg.Wait()
+			w.remoteCache.save()
 			close(w.Statuses)
 			close(w.Errors)
 		}()
@@ -87,7 +99,7 @@ func (w *workspace) uniqueWorker(wg *sync.WaitGroup) {
 		// This is potentially somewhat slow.
 		bpkg, err := build.Import(importPath, wd, build.FindOnly|build.IgnoreVendor)
 		if err != nil {
-			w.Errors <- err
+			w.reportError(importPath, "resolve", Error, err)
 			continue
 		}
 		if bpkg.Goroot {
@@ -117,6 +129,8 @@ func (w *workspace) uniqueWorker(wg *sync.WaitGroup) {
 		vcs, err := vcsstate.NewVCS(vcsCmd)
 		if err != nil {
 			// Repository not supported by vcsstate.
+			w.reportError(root, "vcs", Warn, fmt.Errorf("%v not supported by vcsstate: %v", vcsCmd.Name, err))
+
 			var pkg *Repo
 			w.reposMu.Lock()
 			if _, ok := w.repos[root]; !ok {
@@ -140,9 +154,10 @@ func (w *workspace) uniqueWorker(wg *sync.WaitGroup) {
 		w.reposMu.Lock()
 		if _, ok := w.repos[root]; !ok {
 			repo = &Repo{
-				Path: bpkg.Dir,
-				Root: root,
-				vcs:  vcs,
+				Path:    bpkg.Dir,
+				Root:    root,
+				vcs:     vcs,
+				vcsName: vcsCmd.Cmd,
 			}
 			w.repos[root] = repo
 		}
@@ -155,6 +170,88 @@ func (w *workspace) uniqueWorker(wg *sync.WaitGroup) {
 	}
 }
 
+// DiscoverAll walks every src/ subtree of build.Default.GOPATH, finding
+// VCS-controlled repos directly on disk and feeding them into the unique
+// stage, the same as uniqueWorker does for resolved import paths. It's the
+// backing implementation for -all, for users who want the status of every
+// repo they have checked out rather than supplying an explicit list of
+// import paths.
+//
+// DiscoverAll must be called before w.ImportPaths is closed.
+func (w *workspace) DiscoverAll() {
+	w.uniqueWG.Add(1)
+	go func() {
+		defer w.uniqueWG.Done()
+
+		var wg sync.WaitGroup
+		for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+			srcRoot := filepath.Join(gopath, "src")
+			wg.Add(1)
+			go func(srcRoot string) {
+				defer wg.Done()
+				w.discoverSrcRoot(srcRoot)
+			}(srcRoot)
+		}
+		wg.Wait()
+	}()
+}
+
+// discoverSrcRoot walks srcRoot looking for VCS repository roots, sending
+// newly discovered ones to w.unique. It doesn't descend into a repo once
+// found, nor into dot, underscore, or testdata directories.
+func (w *workspace) discoverSrcRoot(srcRoot string) {
+	walkFn := func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			// Unreadable entry; skip it rather than aborting the whole walk.
+			return nil
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		if name := fi.Name(); path != srcRoot && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+			return filepath.SkipDir
+		}
+
+		vcsCmd, root, err := vcs.FromDir(path, srcRoot)
+		if err != nil {
+			// Not a repository root; keep walking down.
+			return nil
+		}
+
+		w.reposMu.Lock()
+		_, exists := w.repos[root]
+		var repo *Repo
+		if !exists {
+			if vcs, err := vcsstate.NewVCS(vcsCmd); err != nil {
+				w.reportError(root, "vcs", Warn, fmt.Errorf("%v not supported by vcsstate: %v", vcsCmd.Name, err))
+				repo = &Repo{
+					Path:     path,
+					Root:     root,
+					vcsError: fmt.Errorf("%v not supported by vcsstate: %v", vcsCmd.Name, err),
+				}
+			} else {
+				repo = &Repo{
+					Path:    path,
+					Root:    root,
+					vcs:     vcs,
+					vcsName: vcsCmd.Cmd,
+				}
+			}
+			w.repos[root] = repo
+		}
+		w.reposMu.Unlock()
+
+		if repo != nil {
+			w.unique <- repo
+		}
+
+		// Found a repository root; don't descend into it looking for nested ones.
+		return filepath.SkipDir
+	}
+
+	filepath.Walk(srcRoot, walkFn)
+}
+
 // processFilterWorker computes repository local and remote state, and filters with shouldShow.
 func (w *workspace) processFilterWorker(wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -169,7 +266,7 @@ func (w *workspace) processFilterWorker(wg *sync.WaitGroup) {
 	}
 }
 
-func (*workspace) computeVCSState(r *Repo) {
+func (w *workspace) computeVCSState(r *Repo) {
 	if r.vcs == nil {
 		// Go package not under VCS.
 		return
@@ -177,19 +274,53 @@ func (*workspace) computeVCSState(r *Repo) {
 
 	if s, err := r.vcs.Status(r.Path); err == nil {
 		r.Local.Status = s
+	} else {
+		w.reportError(r.Root, "vcs", Warn, err)
 	}
 	if b, err := r.vcs.Branch(r.Path); err == nil {
 		r.Local.Branch = b
+	} else {
+		w.reportError(r.Root, "vcs", Warn, err)
 	}
 	if s, err := r.vcs.Stash(r.Path); err == nil {
 		r.Local.Stash = s
+	} else {
+		w.reportError(r.Root, "vcs", Warn, err)
 	}
 	if remote, err := r.vcs.RemoteURL(r.Path); err == nil {
 		r.Local.RemoteURL = remote
+	} else if err != vcsstate.ErrNoRemote {
+		w.reportError(r.Root, "vcs", Warn, err)
+	}
+	if n, err := changedFileCount(r.vcsName, r.Path); err == nil {
+		r.Local.ChangedFiles = n
+	} else if err != errUnsupportedVCS {
+		w.reportError(r.Root, "vcs", Warn, err)
+	}
+
+	if *noRemote {
+		return
+	}
+
+	if ahead, behind, err := aheadBehindCommitCounts(r.vcsName, r.Path); err == nil {
+		r.Local.AheadCount, r.Local.BehindCount = ahead, behind
+	} else if err != errUnsupportedVCS && err != errNoUpstream {
+		w.reportError(r.Root, "vcs", Warn, err)
 	}
-	if b, rev, remoteError := r.vcs.RemoteBranchAndRevision(r.Path); remoteError == nil {
+
+	// fetchedRemote tracks whether Branch/Revision below came from a fresh
+	// lookup (resolver or VCS), as opposed to a cache hit or a failure path,
+	// so we only ever write back to the cache after an actual fetch.
+	var fetchedRemote bool
+	if cached, ok := w.remoteCache.get(r.Root); ok {
+		r.Remote.Branch, r.Remote.Revision, r.Remote.RepoURL = cached.Branch, cached.Revision, cached.RepoURL
+	} else if b, rev, err := w.remoteResolver.Resolve(r.Local.RemoteURL); err == nil {
+		r.Remote.Branch, r.Remote.Revision = b, rev
+		fetchedRemote = true
+	} else if b, rev, remoteError := r.vcs.RemoteBranchAndRevision(r.Path); remoteError == nil {
 		r.Remote.Branch = b
 		r.Remote.Revision = rev
+		fetchedRemote = true
 	} else if remoteError == vcsstate.ErrNoRemote {
 		r.Remote.Branch = r.vcs.NoRemoteDefaultBranch()
 	} else if notFoundError, ok := remoteError.(vcsstate.NotFoundError); ok {
@@ -199,16 +330,20 @@ func (*workspace) computeVCSState(r *Repo) {
 		if b, err := r.vcs.CachedRemoteDefaultBranch(); err == nil {
 			r.Remote.Branch = b
 		} else {
-			log.Printf("%v: %v\n", r.Root, remoteError)
+			w.reportError(r.Root, "remote", Warn, remoteError)
 			r.Remote.Branch = r.vcs.NoRemoteDefaultBranch() // It's a better fallback than empty string.
 		}
 	}
 	if rev, err := r.vcs.LocalRevision(r.Path, r.Remote.Branch); err == nil {
 		r.Local.Revision = rev
+	} else {
+		w.reportError(r.Root, "vcs", Warn, err)
 	}
 	if r.Remote.Revision != "" {
 		if c, err := r.vcs.Contains(r.Path, r.Remote.Revision, r.Remote.Branch); err == nil {
 			r.Local.ContainsRemoteRevision = c
+		} else {
+			w.reportError(r.Root, "vcs", Warn, err)
 		}
 	}
 	if r.Local.Revision != "" {
@@ -218,10 +353,22 @@ func (*workspace) computeVCSState(r *Repo) {
 			// Fall back to using r.Local.ContainsRemoteRevision to deduct information.
 			// Assume that if local contains remote revision, then remote doesn't, and vice versa.
 			r.Remote.ContainsLocalRevision = !r.Local.ContainsRemoteRevision
+		} else {
+			w.reportError(r.Root, "vcs", Warn, err)
 		}
 	}
-	if rr, err := vcs.RepoRootForImportPath(r.Root, false); err == nil {
-		r.Remote.RepoURL = rr.Repo
+	if r.Remote.RepoURL == "" {
+		if rr, err := vcs.RepoRootForImportPath(r.Root, false); err == nil {
+			r.Remote.RepoURL = rr.Repo
+		}
+	}
+
+	if fetchedRemote && r.Remote.NotFound == nil {
+		w.remoteCache.put(r.Root, remoteCacheEntry{
+			Branch:   r.Remote.Branch,
+			Revision: r.Remote.Revision,
+			RepoURL:  r.Remote.RepoURL,
+		})
 	}
 }
 